@@ -0,0 +1,183 @@
+// Command seq-input-gelf accepts GELF log messages and republishes them
+// to Seq as CLEF events.
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/datalust/seq-input-gelf/internal/clef"
+	"github.com/datalust/seq-input-gelf/internal/gelf"
+	"github.com/datalust/seq-input-gelf/internal/metrics"
+	"github.com/datalust/seq-input-gelf/internal/queue"
+	"github.com/datalust/seq-input-gelf/internal/seq"
+)
+
+func main() {
+	var (
+		protocol            = flag.String("protocol", "udp", "input protocol(s) to enable, comma-separated: udp, tcp, tls, http, https, or all")
+		udpListen           = flag.String("udp-listen", "0.0.0.0:12201", "address to bind the UDP GELF listener")
+		tcpListen           = flag.String("tcp-listen", "0.0.0.0:12201", "address to bind the TCP GELF listener")
+		tcpTLSListen        = flag.String("tcp-tls-listen", "0.0.0.0:12203", "address to bind the TCP/TLS GELF listener")
+		tcpTLSCert          = flag.String("tcp-tls-cert", "", "PEM certificate file for the TCP/TLS listener")
+		tcpTLSKey           = flag.String("tcp-tls-key", "", "PEM private key file for the TCP/TLS listener")
+		tcpTLSCAFile        = flag.String("tcp-tls-client-ca", "", "optional PEM file of client CAs to require mutual TLS on the TCP/TLS listener")
+		tcpMaxFrameBytes    = flag.Int64("tcp-max-frame-bytes", 10<<20, "maximum size of a single null-terminated TCP/TLS GELF frame (0 for unbounded)")
+		chunkBufferBytes    = flag.Int64("chunk-buffer-bytes", 10<<20, "maximum bytes buffered across in-flight chunked UDP messages (0 for unbounded)")
+		maxDecompressedSize = flag.Int64("max-decompressed-bytes", 10<<20, "maximum inflated size of a gzip/zlib-compressed UDP datagram (0 for unbounded)")
+		httpListen          = flag.String("http-listen", "0.0.0.0:12202", "address to bind the HTTP GELF listener")
+		httpTLSListen       = flag.String("http-tls-listen", "0.0.0.0:12204", "address to bind the HTTPS GELF listener")
+		httpTLSCert         = flag.String("http-tls-cert", "", "PEM certificate file for the HTTPS listener")
+		httpTLSKey          = flag.String("http-tls-key", "", "PEM private key file for the HTTPS listener")
+		httpMaxBodyBytes    = flag.Int64("http-max-body-bytes", 10<<20, "maximum size of an HTTP/HTTPS GELF request body once decompressed (0 for unbounded)")
+		seqURL              = flag.String("seq-url", "http://localhost:5341", "base URL of the Seq server to forward events to")
+		seqAPIKey           = flag.String("seq-apikey", "", "API key to present to the Seq server, if required")
+		batchSize           = flag.Int("batch-size", 100, "maximum number of events sent to Seq in one request")
+		flushInterval       = flag.Duration("flush-interval", time.Second, "maximum time to wait before flushing a partial batch to Seq")
+		queueCapacity       = flag.Int("queue-capacity", 10000, "maximum number of events buffered awaiting delivery to Seq")
+		queuePolicyFlag     = flag.String("queue-policy", string(queue.PolicyBlock), "behavior when the queue is full: block, drop-oldest, or drop-new")
+		fieldMappingFile    = flag.String("field-mapping", "", "path to a JSON or YAML file configuring additional GELF field mapping (see FieldMapping)")
+	)
+	flag.Parse()
+
+	var fieldMapping *clef.FieldMapping
+	if *fieldMappingFile != "" {
+		var err error
+		fieldMapping, err = clef.LoadFieldMapping(*fieldMappingFile)
+		if err != nil {
+			log.Fatalf("seq-input-gelf: %s", err)
+		}
+	}
+
+	counters := &metrics.Protocols{}
+
+	q := queue.New(*queueCapacity, queue.Policy(*queuePolicyFlag), counters.AddQueueDropped)
+
+	forwarder := &seq.Forwarder{
+		Queue:         q,
+		Client:        &seq.Client{URL: *seqURL, APIKey: *seqAPIKey, HTTPClient: seq.DefaultHTTPClient},
+		BatchSize:     *batchSize,
+		FlushInterval: *flushInterval,
+	}
+	go forwarder.Run()
+
+	handle := func(c *metrics.Counters) gelf.Handler {
+		return func(m *gelf.Message) {
+			c.AddReceived()
+			q.Push(clef.Translate(m, fieldMapping))
+		}
+	}
+
+	enabled := protocolsFor(*protocol)
+
+	if enabled["udp"] {
+		r := &gelf.UDPReceiver{
+			Addr:                 *udpListen,
+			Handler:              handle(&counters.UDP),
+			ChunkBufferBytes:     *chunkBufferBytes,
+			OnChunkEvicted:       counters.UDP.AddChunkEvicted,
+			MaxDecompressedBytes: *maxDecompressedSize,
+			OnDecompressed:       counters.UDP.AddDecompressed,
+			OnRejected:           counters.UDP.AddRejected,
+		}
+		go mustServe("udp", r.ListenAndServe)
+	}
+
+	if enabled["tcp"] {
+		r := &gelf.TCPReceiver{Addr: *tcpListen, Handler: handle(&counters.TCP), MaxFrameBytes: *tcpMaxFrameBytes, OnRejected: counters.TCP.AddRejected}
+		go mustServe("tcp", r.ListenAndServe)
+	}
+
+	if enabled["tls"] {
+		tlsConfig, err := loadTLSConfig(*tcpTLSCert, *tcpTLSKey, *tcpTLSCAFile)
+		if err != nil {
+			log.Fatalf("seq-input-gelf: configuring TCP/TLS listener: %s", err)
+		}
+		r := &gelf.TCPReceiver{Addr: *tcpTLSListen, TLSConfig: tlsConfig, Handler: handle(&counters.TCP), MaxFrameBytes: *tcpMaxFrameBytes, OnRejected: counters.TCP.AddRejected}
+		go mustServe("tls", r.ListenAndServe)
+	}
+
+	if enabled["http"] {
+		r := &gelf.HTTPReceiver{Addr: *httpListen, Handler: handle(&counters.HTTP), Overloaded: q.Full, MaxBodyBytes: *httpMaxBodyBytes, OnRejected: counters.HTTP.AddRejected}
+		go mustServe("http", r.ListenAndServe)
+	}
+
+	if enabled["https"] {
+		tlsConfig, err := loadTLSConfig(*httpTLSCert, *httpTLSKey, "")
+		if err != nil {
+			log.Fatalf("seq-input-gelf: configuring HTTPS listener: %s", err)
+		}
+		r := &gelf.HTTPReceiver{Addr: *httpTLSListen, TLSConfig: tlsConfig, Handler: handle(&counters.HTTP), Overloaded: q.Full, MaxBodyBytes: *httpMaxBodyBytes, OnRejected: counters.HTTP.AddRejected}
+		go mustServe("https", r.ListenAndServe)
+	}
+
+	logMetricsPeriodically(counters)
+}
+
+// protocolsFor expands the --protocol flag into the set of transports
+// to start.
+func protocolsFor(protocol string) map[string]bool {
+	if protocol == "all" {
+		return map[string]bool{"udp": true, "tcp": true, "tls": true, "http": true, "https": true}
+	}
+
+	enabled := make(map[string]bool)
+	for _, p := range strings.Split(protocol, ",") {
+		enabled[strings.TrimSpace(p)] = true
+	}
+	return enabled
+}
+
+// loadTLSConfig builds a server TLS configuration from a PEM
+// certificate and key, optionally requiring mutual TLS against a CA
+// bundle.
+func loadTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	if certFile == "" || keyFile == "" {
+		log.Fatal("seq-input-gelf: a TLS certificate and key are required for this listener")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	if clientCAFile != "" {
+		pem, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", clientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return cfg, nil
+}
+
+func mustServe(name string, listenAndServe func() error) {
+	if err := listenAndServe(); err != nil {
+		log.Fatalf("seq-input-gelf: %s listener stopped: %s", name, err)
+	}
+}
+
+func logMetricsPeriodically(counters *metrics.Protocols) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		u, t, h := counters.UDP.Snapshot(), counters.TCP.Snapshot(), counters.HTTP.Snapshot()
+		log.Printf("seq-input-gelf: received udp=%d tcp=%d http=%d, rejected udp=%d tcp=%d http=%d, chunks evicted udp=%d, decompressed udp %d->%d bytes, queue dropped=%d",
+			u.Received, t.Received, h.Received, u.Rejected, t.Rejected, h.Rejected, u.ChunksEvicted, u.BytesCompressedIn, u.BytesDecompressedOut,
+			counters.QueueDroppedCount())
+	}
+}
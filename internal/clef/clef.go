@@ -0,0 +1,158 @@
+// Package clef translates decoded GELF messages into the Compact Log
+// Event Format (CLEF) that Seq ingests.
+package clef
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/datalust/seq-input-gelf/internal/gelf"
+)
+
+// Event is a single CLEF log event. The well-known "@"-prefixed fields
+// are marshaled first, followed by the free-form Properties.
+type Event struct {
+	Timestamp       time.Time
+	Level           string
+	MessageTemplate string
+	Exception       string
+	// TopLevel carries fields promoted to CLEF's own "@"-prefixed
+	// namespace (e.g. "@tr", "@sp"), per FieldMapping.Promote.
+	TopLevel   map[string]interface{}
+	Properties map[string]interface{}
+}
+
+// Translate converts a decoded GELF message into a CLEF event, per
+// mapping (which may be nil to apply only the default rules):
+//   - "_"-prefixed additional fields are projected as properties with
+//     the leading underscore stripped
+//   - fields named in mapping.Promote are instead written to CLEF's
+//     "@"-prefixed namespace (e.g. "_trace_id" -> "@tr")
+//   - GELF's syslog-numeric level is mapped to a CLEF level name
+//   - a full_message containing a stack trace (multiple lines) becomes
+//     the event's CLEF exception ("@x") rather than a plain property
+func Translate(m *gelf.Message, mapping *FieldMapping) *Event {
+	props := make(map[string]interface{}, len(m.Additional)+4)
+	top := make(map[string]interface{})
+
+	if m.Host != "" {
+		props["host"] = m.Host
+	}
+	if m.Facility != "" {
+		props["facility"] = m.Facility
+	}
+	if m.Line != 0 {
+		props["line"] = m.Line
+	}
+	if m.File != "" {
+		props["file"] = m.File
+	}
+
+	exception := ""
+	if m.FullMessage != "" {
+		if strings.Contains(m.FullMessage, "\n") {
+			exception = m.FullMessage
+		} else {
+			props["full_message"] = m.FullMessage
+		}
+	}
+
+	for k, v := range m.Additional {
+		if target, ok := mapping.promotion(k); ok {
+			top[target] = v
+			continue
+		}
+		props[strings.TrimPrefix(k, "_")] = v
+	}
+
+	return &Event{
+		Timestamp:       timestampToTime(m.Timestamp),
+		Level:           levelToCLEF(m.Level),
+		MessageTemplate: m.ShortMessage,
+		Exception:       exception,
+		TopLevel:        top,
+		Properties:      props,
+	}
+}
+
+// levelToCLEF maps a GELF (syslog-numeric) level to a CLEF level name.
+func levelToCLEF(level int) string {
+	switch level {
+	case 0, 1, 2: // Emergency, Alert, Critical
+		return "Fatal"
+	case 3: // Error
+		return "Error"
+	case 4: // Warning
+		return "Warning"
+	case 5, 6: // Notice, Informational
+		return "Information"
+	case 7: // Debug
+		return "Debug"
+	default:
+		return "Information"
+	}
+}
+
+func timestampToTime(ts float64) time.Time {
+	if ts == 0 {
+		return time.Now().UTC()
+	}
+	secs := int64(ts)
+	nsecs := int64((ts - float64(secs)) * float64(time.Second))
+	return time.Unix(secs, nsecs).UTC()
+}
+
+// MarshalJSON writes the event in CLEF's compact, single-line form.
+func (e *Event) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	buf.WriteString(`"@t":`)
+	t, _ := json.Marshal(e.Timestamp.Format("2006-01-02T15:04:05.000Z07:00"))
+	buf.Write(t)
+
+	buf.WriteString(`,"@m":`)
+	m, _ := json.Marshal(e.MessageTemplate)
+	buf.Write(m)
+
+	if e.Level != "" && e.Level != "Information" {
+		buf.WriteString(`,"@l":`)
+		l, _ := json.Marshal(e.Level)
+		buf.Write(l)
+	}
+
+	if e.Exception != "" {
+		buf.WriteString(`,"@x":`)
+		x, _ := json.Marshal(e.Exception)
+		buf.Write(x)
+	}
+
+	for k, v := range e.TopLevel {
+		buf.WriteByte(',')
+		key, _ := json.Marshal("@" + strings.TrimPrefix(k, "@"))
+		buf.Write(key)
+		buf.WriteByte(':')
+		val, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+
+	for k, v := range e.Properties {
+		buf.WriteByte(',')
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+		val, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
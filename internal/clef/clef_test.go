@@ -0,0 +1,127 @@
+package clef
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/datalust/seq-input-gelf/internal/gelf"
+)
+
+func TestLevelToCLEF(t *testing.T) {
+	cases := map[int]string{
+		0:  "Fatal",
+		1:  "Fatal",
+		2:  "Fatal",
+		3:  "Error",
+		4:  "Warning",
+		5:  "Information",
+		6:  "Information",
+		7:  "Debug",
+		99: "Information", // unrecognized levels fall back to Information
+	}
+
+	for level, want := range cases {
+		if got := levelToCLEF(level); got != want {
+			t.Errorf("levelToCLEF(%d) = %q, want %q", level, got, want)
+		}
+	}
+}
+
+func TestTranslateDefaultFieldProjection(t *testing.T) {
+	m := &gelf.Message{
+		ShortMessage: "hi",
+		Additional:   map[string]interface{}{"_trace_id": "abc"},
+	}
+
+	e := Translate(m, nil)
+
+	if _, promoted := e.TopLevel["@trace_id"]; promoted {
+		t.Fatal("without a mapping, fields must not be promoted")
+	}
+	if e.Properties["trace_id"] != "abc" {
+		t.Fatalf("expected the leading underscore to be stripped, got %v", e.Properties)
+	}
+}
+
+func TestTranslatePromotesMappedField(t *testing.T) {
+	m := &gelf.Message{
+		ShortMessage: "hi",
+		Additional:   map[string]interface{}{"_trace_id": "abc"},
+	}
+	mapping := &FieldMapping{Promote: map[string]string{"_trace_id": "@tr"}}
+
+	e := Translate(m, mapping)
+
+	if e.TopLevel["@tr"] != "abc" {
+		t.Fatalf("expected _trace_id to be promoted to @tr, got %v", e.TopLevel)
+	}
+	if _, present := e.Properties["trace_id"]; present {
+		t.Fatal("a promoted field must not also appear as a property")
+	}
+}
+
+func TestTranslateMultilineFullMessageBecomesException(t *testing.T) {
+	m := &gelf.Message{
+		ShortMessage: "boom",
+		FullMessage:  "panic: boom\ngoroutine 1 [running]:",
+	}
+
+	e := Translate(m, nil)
+
+	if e.Exception == "" {
+		t.Fatal("a multi-line full_message should be promoted to @x")
+	}
+	if _, present := e.Properties["full_message"]; present {
+		t.Fatal("a full_message promoted to @x must not also be a property")
+	}
+}
+
+func TestTranslateSingleLineFullMessageStaysProperty(t *testing.T) {
+	m := &gelf.Message{
+		ShortMessage: "hi",
+		FullMessage:  "just some extra detail",
+	}
+
+	e := Translate(m, nil)
+
+	if e.Exception != "" {
+		t.Fatal("a single-line full_message must not become @x")
+	}
+	if e.Properties["full_message"] != "just some extra detail" {
+		t.Fatalf("expected full_message to remain a property, got %v", e.Properties)
+	}
+}
+
+func TestEventMarshalJSONOmitsDefaultLevel(t *testing.T) {
+	e := &Event{MessageTemplate: "hi", Level: "Information"}
+
+	b, err := e.MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatalf("invalid JSON produced: %s", err)
+	}
+	if _, present := fields["@l"]; present {
+		t.Fatal("the default Information level should be omitted from @l")
+	}
+}
+
+func TestEventMarshalJSONIncludesNonDefaultLevel(t *testing.T) {
+	e := &Event{MessageTemplate: "hi", Level: "Error"}
+
+	b, err := e.MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatalf("invalid JSON produced: %s", err)
+	}
+	if fields["@l"] != "Error" {
+		t.Fatalf("expected @l to be Error, got %v", fields["@l"])
+	}
+}
@@ -0,0 +1,97 @@
+package clef
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FieldMapping configures how GELF's "_"-prefixed additional fields are
+// projected into CLEF, beyond the default (strip the underscore, keep
+// as a property). It lets operators adapt to a GELF producer's field
+// names, e.g. the Docker GELF driver's "_container_id", without
+// rebuilding the binary.
+type FieldMapping struct {
+	// Promote maps a GELF field name (with its leading underscore, as
+	// it appears on the wire, e.g. "_trace_id") to the CLEF
+	// "@"-prefixed top-level property it should become (e.g. "@tr").
+	Promote map[string]string `json:"promote" yaml:"promote"`
+}
+
+// promotion reports the CLEF top-level name mapping promotes field to,
+// if any. It is nil-safe so callers can pass a nil *FieldMapping to
+// apply only the default translation rules.
+func (fm *FieldMapping) promotion(field string) (string, bool) {
+	if fm == nil || fm.Promote == nil {
+		return "", false
+	}
+	target, ok := fm.Promote[field]
+	return target, ok
+}
+
+// LoadFieldMapping reads a field mapping from a JSON or YAML file,
+// selected by its extension (".json", or ".yaml"/".yml").
+func LoadFieldMapping(path string) (*FieldMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("clef: reading field mapping %s: %w", path, err)
+	}
+
+	var fm FieldMapping
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := unmarshalSimpleYAML(data, &fm); err != nil {
+			return nil, fmt.Errorf("clef: parsing field mapping %s: %w", path, err)
+		}
+	default:
+		if err := json.Unmarshal(data, &fm); err != nil {
+			return nil, fmt.Errorf("clef: parsing field mapping %s: %w", path, err)
+		}
+	}
+
+	return &fm, nil
+}
+
+// unmarshalSimpleYAML parses the small subset of YAML this package
+// needs: a single top-level "promote:" key followed by indented
+// "field: target" pairs. It exists so field mapping files can use the
+// same YAML style as other Seq input configuration without pulling in
+// a full YAML dependency.
+func unmarshalSimpleYAML(data []byte, fm *FieldMapping) error {
+	fm.Promote = make(map[string]string)
+
+	inPromote := false
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimRight(line, " \t\r")
+		if trimmed == "" || strings.HasPrefix(strings.TrimSpace(trimmed), "#") {
+			continue
+		}
+
+		if !strings.HasPrefix(trimmed, " ") && !strings.HasPrefix(trimmed, "\t") {
+			inPromote = strings.TrimSpace(trimmed) == "promote:"
+			continue
+		}
+
+		if !inPromote {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimSpace(trimmed), ":")
+		if !ok {
+			return fmt.Errorf("expected \"field: target\", got %q", trimmed)
+		}
+		fm.Promote[unquote(strings.TrimSpace(key))] = unquote(strings.TrimSpace(value))
+	}
+
+	return nil
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package clef
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFieldMappingPromotionNilSafe(t *testing.T) {
+	var fm *FieldMapping
+	if _, ok := fm.promotion("_trace_id"); ok {
+		t.Fatal("a nil *FieldMapping must apply no promotions")
+	}
+
+	fm = &FieldMapping{}
+	if _, ok := fm.promotion("_trace_id"); ok {
+		t.Fatal("a mapping with no Promote entries must apply no promotions")
+	}
+}
+
+func TestFieldMappingPromotionLookup(t *testing.T) {
+	fm := &FieldMapping{Promote: map[string]string{"_trace_id": "@tr"}}
+
+	target, ok := fm.promotion("_trace_id")
+	if !ok || target != "@tr" {
+		t.Fatalf("got (%q, %v), want (\"@tr\", true)", target, ok)
+	}
+
+	if _, ok := fm.promotion("_unmapped"); ok {
+		t.Fatal("an unmapped field must not be promoted")
+	}
+}
+
+func TestLoadFieldMappingJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapping.json")
+	writeFile(t, path, `{"promote":{"_trace_id":"@tr","_span_id":"@sp"}}`)
+
+	fm, err := LoadFieldMapping(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fm.Promote["_trace_id"] != "@tr" || fm.Promote["_span_id"] != "@sp" {
+		t.Fatalf("got %v", fm.Promote)
+	}
+}
+
+func TestLoadFieldMappingYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapping.yaml")
+	writeFile(t, path, "promote:\n  _trace_id: \"@tr\"\n  _span_id: '@sp'\n# a comment\n\n")
+
+	fm, err := LoadFieldMapping(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fm.Promote["_trace_id"] != "@tr" || fm.Promote["_span_id"] != "@sp" {
+		t.Fatalf("got %v", fm.Promote)
+	}
+}
+
+func TestLoadFieldMappingYAMLMalformedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapping.yml")
+	writeFile(t, path, "promote:\n  not-a-pair\n")
+
+	if _, err := LoadFieldMapping(path); err == nil {
+		t.Fatal("expected an error for a promote line without a \"field: target\" pair")
+	}
+}
+
+func TestLoadFieldMappingMissingFile(t *testing.T) {
+	if _, err := LoadFieldMapping(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing %s: %s", path, err)
+	}
+}
@@ -0,0 +1,120 @@
+package gelf
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// Chunked GELF datagrams are prefixed with this two-byte magic, per the
+// GELF spec.
+var chunkMagic = [2]byte{0x1e, 0x0f}
+
+const (
+	chunkHeaderLen = 2 + 8 + 1 + 1 // magic + message id + sequence number + sequence count
+	maxChunkCount  = 128
+	chunkTimeout   = 5 * time.Second
+)
+
+// chunkReassembler buffers the chunks of in-flight GELF messages until
+// all of a message's chunks have arrived, or it has been waiting longer
+// than chunkTimeout.
+type chunkReassembler struct {
+	maxBufferedBytes int64
+
+	mu       sync.Mutex
+	buffered int64
+	messages map[uint64]*partialMessage
+	onEvict  func()
+}
+
+type partialMessage struct {
+	arrived time.Time
+	total   int
+	chunks  map[int][]byte
+	bytes   int64
+}
+
+// newChunkReassembler returns a reassembler that evicts partial
+// messages once their total buffered payload would exceed
+// maxBufferedBytes. onEvict, if non-nil, is called once per evicted
+// (timed-out) message.
+func newChunkReassembler(maxBufferedBytes int64, onEvict func()) *chunkReassembler {
+	return &chunkReassembler{
+		maxBufferedBytes: maxBufferedBytes,
+		messages:         make(map[uint64]*partialMessage),
+		onEvict:          onEvict,
+	}
+}
+
+// isChunk reports whether payload begins with the GELF chunk magic.
+func isChunk(payload []byte) bool {
+	return len(payload) >= chunkHeaderLen && payload[0] == chunkMagic[0] && payload[1] == chunkMagic[1]
+}
+
+// add processes one chunk datagram, returning the reassembled message
+// once every chunk for its message ID has arrived. ok is false while
+// the message is still incomplete.
+func (r *chunkReassembler) add(payload []byte) (reassembled []byte, ok bool) {
+	id := binary.BigEndian.Uint64(payload[2:10])
+	seq := int(payload[10])
+	total := int(payload[11])
+	data := payload[chunkHeaderLen:]
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.evictExpiredLocked()
+
+	if total <= 0 || total > maxChunkCount || seq < 0 || seq >= total {
+		return nil, false
+	}
+
+	pm, exists := r.messages[id]
+	if !exists {
+		pm = &partialMessage{arrived: time.Now(), total: total, chunks: make(map[int][]byte, total)}
+		r.messages[id] = pm
+	}
+
+	if _, dup := pm.chunks[seq]; !dup {
+		pm.chunks[seq] = data
+		pm.bytes += int64(len(data))
+		r.buffered += int64(len(data))
+	}
+
+	if r.maxBufferedBytes > 0 && r.buffered > r.maxBufferedBytes {
+		r.deleteLocked(id)
+		return nil, false
+	}
+
+	if len(pm.chunks) < pm.total {
+		return nil, false
+	}
+
+	out := make([]byte, 0, pm.bytes)
+	for i := 0; i < pm.total; i++ {
+		out = append(out, pm.chunks[i]...)
+	}
+	r.deleteLocked(id)
+
+	return out, true
+}
+
+func (r *chunkReassembler) deleteLocked(id uint64) {
+	if pm, ok := r.messages[id]; ok {
+		r.buffered -= pm.bytes
+		delete(r.messages, id)
+	}
+}
+
+func (r *chunkReassembler) evictExpiredLocked() {
+	now := time.Now()
+	for id, pm := range r.messages {
+		if now.Sub(pm.arrived) > chunkTimeout {
+			r.deleteLocked(id)
+			if r.onEvict != nil {
+				r.onEvict()
+			}
+		}
+	}
+}
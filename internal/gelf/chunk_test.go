@@ -0,0 +1,174 @@
+package gelf
+
+import (
+	"testing"
+	"time"
+)
+
+func makeChunk(id uint64, seq, total int, data []byte) []byte {
+	header := make([]byte, chunkHeaderLen)
+	header[0], header[1] = chunkMagic[0], chunkMagic[1]
+	for i := 0; i < 8; i++ {
+		header[2+i] = byte(id >> (56 - 8*i))
+	}
+	header[10] = byte(seq)
+	header[11] = byte(total)
+	return append(header, data...)
+}
+
+func TestIsChunk(t *testing.T) {
+	if !isChunk(makeChunk(1, 0, 2, []byte("a"))) {
+		t.Fatal("expected chunk magic to be recognized")
+	}
+	if isChunk([]byte(`{"short_message":"hi"}`)) {
+		t.Fatal("plain JSON must not be mistaken for a chunk")
+	}
+	if isChunk([]byte{0x1e}) {
+		t.Fatal("a payload shorter than the chunk header must not be recognized")
+	}
+}
+
+func TestChunkReassemblerInOrder(t *testing.T) {
+	r := newChunkReassembler(0, nil)
+
+	if _, ok := r.add(makeChunk(1, 0, 2, []byte("hello "))); ok {
+		t.Fatal("message should still be incomplete after one of two chunks")
+	}
+
+	out, ok := r.add(makeChunk(1, 1, 2, []byte("world")))
+	if !ok {
+		t.Fatal("message should be complete after its second chunk arrives")
+	}
+	if string(out) != "hello world" {
+		t.Fatalf("got %q, want %q", out, "hello world")
+	}
+}
+
+func TestChunkReassemblerOutOfOrder(t *testing.T) {
+	r := newChunkReassembler(0, nil)
+
+	if _, ok := r.add(makeChunk(1, 1, 2, []byte("world"))); ok {
+		t.Fatal("message should still be incomplete after one of two chunks")
+	}
+
+	out, ok := r.add(makeChunk(1, 0, 2, []byte("hello ")))
+	if !ok {
+		t.Fatal("message should be complete once both chunks have arrived")
+	}
+	if string(out) != "hello world" {
+		t.Fatalf("got %q, want %q", out, "hello world")
+	}
+}
+
+func TestChunkReassemblerDuplicateChunkIgnored(t *testing.T) {
+	r := newChunkReassembler(0, nil)
+
+	r.add(makeChunk(1, 0, 2, []byte("hello ")))
+	r.add(makeChunk(1, 0, 2, []byte("hello "))) // duplicate of chunk 0
+
+	out, ok := r.add(makeChunk(1, 1, 2, []byte("world")))
+	if !ok {
+		t.Fatal("message should be complete after its second distinct chunk arrives")
+	}
+	if string(out) != "hello world" {
+		t.Fatalf("got %q, want %q", out, "hello world")
+	}
+	if r.buffered != 0 {
+		t.Fatalf("buffered should be fully reclaimed on completion, got %d", r.buffered)
+	}
+}
+
+func TestChunkReassemblerBufferBytesCap(t *testing.T) {
+	var evicted int
+	r := newChunkReassembler(4, func() { evicted++ })
+
+	// The first chunk alone exceeds the 4-byte budget, so the partial
+	// message is dropped rather than buffered indefinitely.
+	if _, ok := r.add(makeChunk(1, 0, 2, []byte("hello "))); ok {
+		t.Fatal("message should not complete when it exceeds the buffer cap")
+	}
+	if len(r.messages) != 0 {
+		t.Fatalf("message exceeding the buffer cap should be evicted, got %d still buffered", len(r.messages))
+	}
+
+	// Completing the message (now within budget) should succeed on a
+	// fresh attempt.
+	r2 := newChunkReassembler(4, nil)
+	r2.add(makeChunk(2, 0, 2, []byte("ab")))
+	out, ok := r2.add(makeChunk(2, 1, 2, []byte("cd")))
+	if !ok || string(out) != "abcd" {
+		t.Fatalf("expected a message within the buffer cap to complete, got %q, %v", out, ok)
+	}
+}
+
+func TestChunkReassemblerTimeoutEviction(t *testing.T) {
+	var evicted int
+	r := newChunkReassembler(0, func() { evicted++ })
+
+	r.add(makeChunk(1, 0, 2, []byte("hello ")))
+
+	// Simulate the chunk having arrived long enough ago to time out,
+	// without sleeping chunkTimeout in the test.
+	r.mu.Lock()
+	r.messages[1].arrived = time.Now().Add(-2 * chunkTimeout)
+	r.mu.Unlock()
+
+	// Any subsequent call sweeps expired messages before handling its
+	// own chunk.
+	if _, ok := r.add(makeChunk(2, 0, 2, []byte("x"))); ok {
+		t.Fatal("unrelated message should still be incomplete")
+	}
+
+	if evicted != 1 {
+		t.Fatalf("expected the timed-out message to be evicted once, got %d", evicted)
+	}
+	if _, stillBuffered := r.messages[1]; stillBuffered {
+		t.Fatal("timed-out message should have been removed")
+	}
+}
+
+func TestChunkReassemblerTotalExceedsMaxChunkCount(t *testing.T) {
+	r := newChunkReassembler(0, nil)
+
+	if _, ok := r.add(makeChunk(1, 0, maxChunkCount+1, []byte("x"))); ok {
+		t.Fatal("a message claiming more than maxChunkCount chunks must never complete")
+	}
+	if len(r.messages) != 0 {
+		t.Fatal("a message exceeding maxChunkCount must not be buffered")
+	}
+}
+
+func TestChunkReassemblerRejectsZeroTotal(t *testing.T) {
+	r := newChunkReassembler(0, nil)
+
+	if _, ok := r.add(makeChunk(1, 0, 0, []byte("x"))); ok {
+		t.Fatal("a chunk claiming a total of 0 must never complete")
+	}
+	if len(r.messages) != 0 {
+		t.Fatal("a chunk claiming a total of 0 must not be buffered")
+	}
+}
+
+func TestChunkReassemblerRejectsOutOfRangeSeq(t *testing.T) {
+	r := newChunkReassembler(0, nil)
+
+	// Two out-of-range sequence numbers against a total of 2 must never
+	// be mistaken for a complete message, and must not corrupt the
+	// count of genuinely buffered chunks.
+	if _, ok := r.add(makeChunk(1, 5, 2, []byte("bogus"))); ok {
+		t.Fatal("a chunk with seq >= total must be rejected, not buffered")
+	}
+	if _, ok := r.add(makeChunk(1, 6, 2, []byte("bogus"))); ok {
+		t.Fatal("a chunk with seq >= total must be rejected, not buffered")
+	}
+	if len(r.messages) != 0 {
+		t.Fatal("out-of-range chunks must not create a partial message")
+	}
+
+	// The message can still be completed normally afterwards.
+	r.add(makeChunk(1, 0, 2, []byte("hello ")))
+	out, ok := r.add(makeChunk(1, 1, 2, []byte("world")))
+	if !ok || string(out) != "hello world" {
+		t.Fatalf("got %q, %v, want \"hello world\", true", out, ok)
+	}
+}
@@ -0,0 +1,71 @@
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// maxDecompressSniffLen is the longest prefix decompress inspects to
+// identify a payload's encoding.
+const maxDecompressSniffLen = 2
+
+// decompress inspects payload's leading bytes and transparently
+// inflates gzip- or zlib-compressed GELF payloads. Uncompressed JSON
+// (starting with '{') is returned unchanged. maxDecompressedBytes
+// bounds the inflated size to guard against decompression bombs; zero
+// means unbounded.
+func decompress(payload []byte, maxDecompressedBytes int64) ([]byte, error) {
+	if len(payload) < maxDecompressSniffLen {
+		return payload, nil
+	}
+
+	switch {
+	case payload[0] == '{':
+		return payload, nil
+
+	case payload[0] == 0x1f && payload[1] == 0x8b:
+		r, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("gelf: opening gzip payload: %w", err)
+		}
+		defer r.Close()
+		return readLimited(r, maxDecompressedBytes)
+
+	case payload[0] == 0x78 && (payload[1] == 0x01 || payload[1] == 0x9c || payload[1] == 0xda):
+		r, err := zlib.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("gelf: opening zlib payload: %w", err)
+		}
+		defer r.Close()
+		return readLimited(r, maxDecompressedBytes)
+
+	default:
+		return payload, nil
+	}
+}
+
+// readLimited reads r fully, capping it at maxBytes (zero means
+// unbounded). It is shared by UDP decompression and HTTP body reading so
+// both paths bound a peer-controlled (and, for gzip/zlib, possibly
+// inflated) size the same way.
+func readLimited(r io.Reader, maxBytes int64) ([]byte, error) {
+	if maxBytes > 0 {
+		// Read one byte past the limit so we can tell a message that
+		// exactly fills the budget apart from one that overflows it.
+		r = io.LimitReader(r, maxBytes+1)
+	}
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("gelf: reading payload: %w", err)
+	}
+
+	if maxBytes > 0 && int64(len(out)) > maxBytes {
+		return nil, fmt.Errorf("gelf: payload exceeds %d byte limit", maxBytes)
+	}
+
+	return out, nil
+}
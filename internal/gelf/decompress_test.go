@@ -0,0 +1,103 @@
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"strings"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatalf("gzip write: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("gzip close: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func zlibBytes(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatalf("zlib write: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("zlib close: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompressUncompressedJSONPassesThrough(t *testing.T) {
+	payload := []byte(`{"short_message":"hi"}`)
+	out, err := decompress(payload, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !bytes.Equal(out, payload) {
+		t.Fatalf("got %q, want unchanged %q", out, payload)
+	}
+}
+
+func TestDecompressShortPayloadFallsThrough(t *testing.T) {
+	out, err := decompress([]byte{'{'}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !bytes.Equal(out, []byte{'{'}) {
+		t.Fatalf("got %q, want unchanged payload", out)
+	}
+}
+
+func TestDecompressGzip(t *testing.T) {
+	want := `{"short_message":"from gzip"}`
+	out, err := decompress(gzipBytes(t, want), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(out) != want {
+		t.Fatalf("got %q, want %q", out, want)
+	}
+}
+
+func TestDecompressZlib(t *testing.T) {
+	want := `{"short_message":"from zlib"}`
+	out, err := decompress(zlibBytes(t, want), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(out) != want {
+		t.Fatalf("got %q, want %q", out, want)
+	}
+}
+
+func TestDecompressBombLimitRejected(t *testing.T) {
+	payload := gzipBytes(t, strings.Repeat("x", 1024))
+	if _, err := decompress(payload, 16); err == nil {
+		t.Fatal("expected decompressed payload exceeding the limit to be rejected")
+	}
+}
+
+func TestDecompressBombLimitExactFitAccepted(t *testing.T) {
+	want := strings.Repeat("x", 16)
+	out, err := decompress(gzipBytes(t, want), 16)
+	if err != nil {
+		t.Fatalf("unexpected error for a payload that exactly fills the limit: %s", err)
+	}
+	if string(out) != want {
+		t.Fatalf("got %q, want %q", out, want)
+	}
+}
+
+func TestDecompressCorruptGzipRejected(t *testing.T) {
+	payload := []byte{0x1f, 0x8b, 0xff, 0xff}
+	if _, err := decompress(payload, 0); err == nil {
+		t.Fatal("expected an error opening a corrupt gzip stream")
+	}
+}
@@ -0,0 +1,6 @@
+package gelf
+
+// Handler receives messages decoded from any transport (UDP, TCP, HTTP).
+// Each call receives a freshly decoded message that the receiver does not
+// reuse, so implementations may retain m.
+type Handler func(m *Message)
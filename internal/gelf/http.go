@@ -0,0 +1,151 @@
+package gelf
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// HTTPReceiver listens for GELF messages delivered as HTTP POSTs, per
+// the convention used by Graylog's HTTP GELF input: a single JSON
+// message (Content-Type: application/json) or newline-delimited batch
+// (Content-Type: application/x-ndjson), optionally gzip-compressed.
+type HTTPReceiver struct {
+	Addr      string
+	TLSConfig *tls.Config
+	Handler   Handler
+
+	// Overloaded, if set, is consulted for every request; when it
+	// returns true the receiver responds 429 without decoding the
+	// body, signalling backpressure to the sender.
+	Overloaded func() bool
+
+	// MaxBodyBytes bounds the size of a request body once decompressed;
+	// zero means unbounded. It guards against decompression bombs on a
+	// gzip-encoded body the same way UDP's MaxDecompressedBytes does.
+	MaxBodyBytes int64
+
+	// OnRejected, if set, is called once for each request rejected
+	// because its body failed to decompress or decode.
+	OnRejected func()
+}
+
+// ListenAndServe binds the receiver's address and serves requests
+// until the server is closed or an unrecoverable error occurs.
+func (r *HTTPReceiver) ListenAndServe() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/gelf", r.serveGELF)
+
+	server := &http.Server{
+		Addr:      r.Addr,
+		Handler:   mux,
+		TLSConfig: r.TLSConfig,
+	}
+
+	proto := "HTTP"
+	if r.TLSConfig != nil {
+		proto = "HTTPS"
+	}
+	log.Printf("gelf: listening for %s on %s", proto, r.Addr)
+
+	if r.TLSConfig != nil {
+		return server.ListenAndServeTLS("", "")
+	}
+	return server.ListenAndServe()
+}
+
+func (r *HTTPReceiver) serveGELF(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.Overloaded != nil && r.Overloaded() {
+		http.Error(w, "backpressure: try again later", http.StatusTooManyRequests)
+		return
+	}
+
+	body := req.Body
+	if strings.EqualFold(req.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid gzip body: %s", err), http.StatusBadRequest)
+			if r.OnRejected != nil {
+				r.OnRejected()
+			}
+			return
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	var err error
+	switch {
+	case strings.Contains(contentType, "x-ndjson"):
+		err = r.handleBatch(body)
+	default:
+		err = r.handleSingle(body)
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		if r.OnRejected != nil {
+			r.OnRejected()
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (r *HTTPReceiver) handleSingle(body io.Reader) error {
+	raw, err := readLimited(body, r.MaxBodyBytes)
+	if err != nil {
+		return fmt.Errorf("reading body: %w", err)
+	}
+
+	m, err := Decode(raw)
+	if err != nil {
+		return err
+	}
+	r.Handler(m)
+	return nil
+}
+
+func (r *HTTPReceiver) handleBatch(body io.Reader) error {
+	if r.MaxBodyBytes > 0 {
+		body = io.LimitReader(body, r.MaxBodyBytes+1)
+	}
+
+	// A single ndjson line must be allowed up to the same size as a
+	// whole single-message body (MaxBodyBytes), so a large full_message
+	// isn't rejected in batch mode alone; maxUDPDatagram is only the
+	// floor for an unbounded (MaxBodyBytes <= 0) configuration.
+	maxLine := maxUDPDatagram
+	if r.MaxBodyBytes > int64(maxLine) {
+		maxLine = int(r.MaxBodyBytes)
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		m, err := Decode(line)
+		if err != nil {
+			return err
+		}
+		r.Handler(m)
+	}
+	return scanner.Err()
+}
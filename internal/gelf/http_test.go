@@ -0,0 +1,194 @@
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func postGELF(t *testing.T, r *HTTPReceiver, contentType, contentEncoding string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/gelf", bytes.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	rec := httptest.NewRecorder()
+	r.serveGELF(rec, req)
+	return rec
+}
+
+func gzipBody(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatalf("gzip write: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("gzip close: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func TestHTTPReceiverSingleMessage(t *testing.T) {
+	var got []*Message
+	r := &HTTPReceiver{Handler: func(m *Message) { got = append(got, m) }}
+
+	rec := postGELF(t, r, "application/json", "", []byte(`{"short_message":"hi"}`))
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	if len(got) != 1 || got[0].ShortMessage != "hi" {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestHTTPReceiverBatchMessages(t *testing.T) {
+	var got []*Message
+	r := &HTTPReceiver{Handler: func(m *Message) { got = append(got, m) }}
+
+	body := []byte("{\"short_message\":\"one\"}\n{\"short_message\":\"two\"}\n")
+	rec := postGELF(t, r, "application/x-ndjson", "", body)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	if len(got) != 2 || got[0].ShortMessage != "one" || got[1].ShortMessage != "two" {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestHTTPReceiverBatchSkipsBlankLines(t *testing.T) {
+	var got []*Message
+	r := &HTTPReceiver{Handler: func(m *Message) { got = append(got, m) }}
+
+	body := []byte("{\"short_message\":\"one\"}\n\n{\"short_message\":\"two\"}\n")
+	postGELF(t, r, "application/x-ndjson", "", body)
+
+	if len(got) != 2 {
+		t.Fatalf("got %d messages, want 2", len(got))
+	}
+}
+
+func TestHTTPReceiverGzipSingleMessage(t *testing.T) {
+	var got []*Message
+	r := &HTTPReceiver{Handler: func(m *Message) { got = append(got, m) }}
+
+	rec := postGELF(t, r, "application/json", "gzip", gzipBody(t, `{"short_message":"from gzip"}`))
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	if len(got) != 1 || got[0].ShortMessage != "from gzip" {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestHTTPReceiverGzipBatch(t *testing.T) {
+	var got []*Message
+	r := &HTTPReceiver{Handler: func(m *Message) { got = append(got, m) }}
+
+	body := "{\"short_message\":\"one\"}\n{\"short_message\":\"two\"}\n"
+	rec := postGELF(t, r, "application/x-ndjson", "gzip", gzipBody(t, body))
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestHTTPReceiverInvalidGzipRejected(t *testing.T) {
+	var rejected int
+	r := &HTTPReceiver{Handler: func(m *Message) {}, OnRejected: func() { rejected++ }}
+
+	rec := postGELF(t, r, "application/json", "gzip", []byte("not gzip"))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rejected != 1 {
+		t.Fatalf("expected OnRejected to fire once, got %d", rejected)
+	}
+}
+
+func TestHTTPReceiverInvalidJSONRejected(t *testing.T) {
+	var rejected int
+	r := &HTTPReceiver{Handler: func(m *Message) {}, OnRejected: func() { rejected++ }}
+
+	rec := postGELF(t, r, "application/json", "", []byte("not json"))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rejected != 1 {
+		t.Fatalf("expected OnRejected to fire once, got %d", rejected)
+	}
+}
+
+func TestHTTPReceiverMaxBodyBytesRejectsOversizedSingle(t *testing.T) {
+	r := &HTTPReceiver{Handler: func(m *Message) {}, MaxBodyBytes: 16}
+
+	body := []byte(`{"short_message":"this body is longer than 16 bytes"}`)
+	rec := postGELF(t, r, "application/json", "", body)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHTTPReceiverMaxBodyBytesAllowsLargeBatchLine(t *testing.T) {
+	// Regression: a single ndjson line bigger than the 64KiB UDP
+	// datagram size, but within MaxBodyBytes, must be accepted the same
+	// way an equivalent single-message POST is.
+	var got []*Message
+	r := &HTTPReceiver{Handler: func(m *Message) { got = append(got, m) }, MaxBodyBytes: 1 << 20}
+
+	large := strings.Repeat("x", maxUDPDatagram+1024)
+	body := []byte(`{"short_message":"big","full_message":"` + large + `"}` + "\n")
+	rec := postGELF(t, r, "application/x-ndjson", "", body)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d messages, want 1", len(got))
+	}
+}
+
+func TestHTTPReceiverBackpressure(t *testing.T) {
+	called := false
+	r := &HTTPReceiver{
+		Handler:    func(m *Message) { called = true },
+		Overloaded: func() bool { return true },
+	}
+
+	rec := postGELF(t, r, "application/json", "", []byte(`{"short_message":"hi"}`))
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if called {
+		t.Fatal("the handler must not run once overloaded")
+	}
+}
+
+func TestHTTPReceiverMethodNotAllowed(t *testing.T) {
+	r := &HTTPReceiver{Handler: func(m *Message) {}}
+
+	req := httptest.NewRequest(http.MethodGet, "/gelf", nil)
+	rec := httptest.NewRecorder()
+	r.serveGELF(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,91 @@
+// Package gelf decodes GELF messages and exposes listeners for the
+// transports (UDP, TCP, HTTP) that can deliver them.
+package gelf
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Message is the decoded form of a GELF payload, per the GELF spec
+// (http://docs.graylog.org/en/latest/pages/gelf.html). Fields that are
+// not part of the core spec are carried in Additional, keyed by their
+// original (underscore-prefixed) name.
+type Message struct {
+	Version      string
+	Host         string
+	ShortMessage string
+	FullMessage  string
+	Timestamp    float64
+	Level        int
+	Facility     string
+	Line         int
+	File         string
+	Additional   map[string]interface{}
+}
+
+// Decode parses a single GELF JSON document. Callers are responsible for
+// any chunk reassembly or decompression before the bytes reach here.
+func Decode(raw []byte) (*Message, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("gelf: decoding message: %w", err)
+	}
+
+	m := &Message{
+		Level:      defaultLevel,
+		Additional: make(map[string]interface{}),
+	}
+
+	for k, v := range fields {
+		switch k {
+		case "version":
+			m.Version, _ = v.(string)
+		case "host":
+			m.Host, _ = v.(string)
+		case "short_message":
+			m.ShortMessage, _ = v.(string)
+		case "full_message":
+			m.FullMessage, _ = v.(string)
+		case "timestamp":
+			m.Timestamp, _ = asFloat(v)
+		case "level":
+			f, _ := asFloat(v)
+			m.Level = int(f)
+		case "facility":
+			m.Facility, _ = v.(string)
+		case "line":
+			f, _ := asFloat(v)
+			m.Line = int(f)
+		case "file":
+			m.File, _ = v.(string)
+		default:
+			m.Additional[k] = v
+		}
+	}
+
+	if m.ShortMessage == "" {
+		return nil, fmt.Errorf("gelf: message is missing required field \"short_message\"")
+	}
+
+	return m, nil
+}
+
+// defaultLevel is the syslog-numeric level GELF assigns a message whose
+// "level" field is absent: 6 (Informational), per the GELF spec. Most
+// GELF producers omit the field for routine messages, so treating a
+// missing level the same as an explicit 0 (Emergency) would misclassify
+// the majority of incoming messages as the most severe possible.
+const defaultLevel = 6
+
+func asFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,116 @@
+package gelf
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"log"
+	"math"
+	"net"
+)
+
+// TCPReceiver listens for GELF messages delivered over TCP, one
+// null-byte-terminated JSON frame per message, per the GELF-over-TCP
+// convention. If TLSConfig is non-nil the listener requires TLS
+// (optionally mutual TLS, via TLSConfig.ClientCAs/ClientAuth).
+type TCPReceiver struct {
+	Addr      string
+	TLSConfig *tls.Config
+	Handler   Handler
+
+	// MaxFrameBytes bounds the size of a single null-terminated frame;
+	// zero means unbounded. It guards against a client that never sends
+	// the delimiter forcing unbounded buffering of one connection, the
+	// same way maxUDPDatagram bounds a single UDP read.
+	MaxFrameBytes int64
+
+	// OnRejected, if set, is called once for each frame discarded
+	// because it failed to decode.
+	OnRejected func()
+}
+
+// ListenAndServe binds the receiver's address and serves connections
+// until the listener is closed or an unrecoverable error occurs.
+func (r *TCPReceiver) ListenAndServe() error {
+	var l net.Listener
+	var err error
+
+	if r.TLSConfig != nil {
+		l, err = tls.Listen("tcp", r.Addr, r.TLSConfig)
+	} else {
+		l, err = net.Listen("tcp", r.Addr)
+	}
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	proto := "TCP"
+	if r.TLSConfig != nil {
+		proto = "TCP/TLS"
+	}
+	log.Printf("gelf: listening for %s on %s", proto, l.Addr())
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go r.serve(conn)
+	}
+}
+
+func (r *TCPReceiver) serve(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Split(splitNULFrames)
+	if r.MaxFrameBytes > 0 {
+		// bufio.Scanner's actual token limit is the larger of the
+		// initial buffer's capacity and this max, so the initial
+		// buffer must never be allowed to exceed MaxFrameBytes itself.
+		initial := 64 * 1024
+		if int(r.MaxFrameBytes) < initial {
+			initial = int(r.MaxFrameBytes)
+		}
+		scanner.Buffer(make([]byte, 0, initial), int(r.MaxFrameBytes))
+	} else {
+		// Lift bufio.Scanner's default 64KiB token limit.
+		scanner.Buffer(make([]byte, 0, 64*1024), math.MaxInt)
+	}
+
+	for scanner.Scan() {
+		frame := scanner.Bytes()
+		if len(frame) == 0 {
+			continue
+		}
+
+		m, err := Decode(frame)
+		if err != nil {
+			log.Printf("gelf: discarding TCP frame from %s: %s", conn.RemoteAddr(), err)
+			if r.OnRejected != nil {
+				r.OnRejected()
+			}
+			continue
+		}
+		r.Handler(m)
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Printf("gelf: closing TCP connection from %s: %s", conn.RemoteAddr(), err)
+	}
+}
+
+// splitNULFrames is a bufio.SplitFunc that delimits frames on a NUL
+// byte, per the GELF-over-TCP convention. At EOF it also yields a final
+// unterminated frame instead of discarding it, so a connection that
+// closes without a trailing NUL still delivers its last message.
+func splitNULFrames(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
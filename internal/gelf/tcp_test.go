@@ -0,0 +1,138 @@
+package gelf
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSplitNULFrames(t *testing.T) {
+	cases := []struct {
+		name        string
+		data        string
+		atEOF       bool
+		wantAdvance int
+		wantToken   string
+	}{
+		{"delimited frame", "hello\x00world", false, 6, "hello"},
+		{"no delimiter, not at EOF", "hello", false, 0, ""},
+		{"no delimiter, at EOF", "hello", true, 5, "hello"},
+		{"empty, at EOF", "", true, 0, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			advance, token, err := splitNULFrames([]byte(c.data), c.atEOF)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if advance != c.wantAdvance || string(token) != c.wantToken {
+				t.Fatalf("got (advance=%d, token=%q), want (advance=%d, token=%q)", advance, token, c.wantAdvance, c.wantToken)
+			}
+		})
+	}
+}
+
+// serveOnPipe runs r.serve against one end of an in-memory connection
+// and returns the other end for the test to drive, plus the channel of
+// decoded messages r.Handler receives.
+func serveOnPipe(r *TCPReceiver) (client net.Conn, messages chan *Message) {
+	server, client := net.Pipe()
+	messages = make(chan *Message, 16)
+	r.Handler = func(m *Message) { messages <- m }
+	go r.serve(server)
+	return client, messages
+}
+
+func recvMessage(t *testing.T, messages chan *Message) *Message {
+	t.Helper()
+	select {
+	case m := <-messages:
+		return m
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a decoded message")
+		return nil
+	}
+}
+
+func TestTCPReceiverDecodesMultipleFramesOnOneConnection(t *testing.T) {
+	client, messages := serveOnPipe(&TCPReceiver{})
+	defer client.Close()
+
+	go client.Write([]byte("{\"short_message\":\"one\"}\x00{\"short_message\":\"two\"}\x00"))
+
+	if got := recvMessage(t, messages).ShortMessage; got != "one" {
+		t.Fatalf("got %q, want %q", got, "one")
+	}
+	if got := recvMessage(t, messages).ShortMessage; got != "two" {
+		t.Fatalf("got %q, want %q", got, "two")
+	}
+}
+
+func TestTCPReceiverFrameSplitAcrossWrites(t *testing.T) {
+	client, messages := serveOnPipe(&TCPReceiver{})
+	defer client.Close()
+
+	go func() {
+		client.Write([]byte(`{"short_mess`))
+		client.Write([]byte(`age":"split"}` + "\x00"))
+	}()
+
+	if got := recvMessage(t, messages).ShortMessage; got != "split" {
+		t.Fatalf("got %q, want %q", got, "split")
+	}
+}
+
+func TestTCPReceiverDeliversUnterminatedFinalFrame(t *testing.T) {
+	client, messages := serveOnPipe(&TCPReceiver{})
+
+	go func() {
+		client.Write([]byte(`{"short_message":"no trailing nul"}`))
+		client.Close()
+	}()
+
+	if got := recvMessage(t, messages).ShortMessage; got != "no trailing nul" {
+		t.Fatalf("got %q, want %q", got, "no trailing nul")
+	}
+}
+
+func TestTCPReceiverDiscardsUndecodableFrameAndKeepsReading(t *testing.T) {
+	var rejected int
+	client, messages := serveOnPipe(&TCPReceiver{OnRejected: func() { rejected++ }})
+	defer client.Close()
+
+	go client.Write([]byte("not json\x00{\"short_message\":\"after the bad frame\"}\x00"))
+
+	if got := recvMessage(t, messages).ShortMessage; got != "after the bad frame" {
+		t.Fatalf("got %q, want %q", got, "after the bad frame")
+	}
+	if rejected != 1 {
+		t.Fatalf("expected OnRejected to fire once, got %d", rejected)
+	}
+}
+
+func TestTCPReceiverMaxFrameBytesClosesConnection(t *testing.T) {
+	client, messages := serveOnPipe(&TCPReceiver{MaxFrameBytes: 8})
+
+	done := make(chan struct{})
+	go func() {
+		// A frame well past the 8-byte cap, with no delimiter in the
+		// first MaxFrameBytes, must not be buffered indefinitely.
+		client.Write([]byte(`{"short_message":"this is far longer than the cap"}` + "\x00"))
+		close(done)
+	}()
+
+	select {
+	case <-messages:
+		t.Fatal("an oversized frame must never be decoded")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The client's Write only returns once the server closes its end.
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the connection should be closed once a frame exceeds MaxFrameBytes")
+	}
+	client.Close()
+}
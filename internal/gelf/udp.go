@@ -0,0 +1,107 @@
+package gelf
+
+import (
+	"log"
+	"net"
+)
+
+// maxUDPDatagram is large enough for any single, unfragmented UDP
+// datagram; IP fragmentation and GELF chunking both produce payloads
+// no larger than this.
+const maxUDPDatagram = 65536
+
+// UDPReceiver listens for GELF messages delivered as UDP datagrams,
+// reassembling GELF's chunked datagrams when present.
+type UDPReceiver struct {
+	Addr    string
+	Handler Handler
+
+	// ChunkBufferBytes bounds the total payload buffered across all
+	// in-flight chunked messages; zero means unbounded. Once exceeded,
+	// the message whose chunk took the total over the limit is dropped;
+	// other in-flight messages are kept.
+	ChunkBufferBytes int64
+
+	// OnChunkEvicted, if set, is called once for each chunked message
+	// dropped after sitting incomplete for longer than the GELF
+	// chunking timeout.
+	OnChunkEvicted func()
+
+	// MaxDecompressedBytes bounds the inflated size of a gzip- or
+	// zlib-compressed datagram; zero means unbounded.
+	MaxDecompressedBytes int64
+
+	// OnDecompressed, if set, is called after each compressed datagram
+	// is inflated, with the compressed and decompressed byte counts.
+	OnDecompressed func(compressedBytes, decompressedBytes int)
+
+	// OnRejected, if set, is called once for each datagram discarded
+	// because it failed to decompress or decode.
+	OnRejected func()
+
+	reassembler *chunkReassembler
+}
+
+// ListenAndServe binds the receiver's address and processes datagrams
+// until the listener is closed or an unrecoverable error occurs.
+func (r *UDPReceiver) ListenAndServe() error {
+	addr, err := net.ResolveUDPAddr("udp", r.Addr)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	r.reassembler = newChunkReassembler(r.ChunkBufferBytes, r.OnChunkEvicted)
+
+	log.Printf("gelf: listening for UDP on %s", conn.LocalAddr())
+
+	buf := make([]byte, maxUDPDatagram)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return err
+		}
+
+		payload := make([]byte, n)
+		copy(payload, buf[:n])
+		r.handle(payload)
+	}
+}
+
+func (r *UDPReceiver) handle(payload []byte) {
+	if isChunk(payload) {
+		reassembled, ok := r.reassembler.add(payload)
+		if !ok {
+			return
+		}
+		payload = reassembled
+	}
+
+	compressedLen := len(payload)
+	payload, err := decompress(payload, r.MaxDecompressedBytes)
+	if err != nil {
+		log.Printf("gelf: discarding UDP datagram: %s", err)
+		if r.OnRejected != nil {
+			r.OnRejected()
+		}
+		return
+	}
+	if len(payload) != compressedLen && r.OnDecompressed != nil {
+		r.OnDecompressed(compressedLen, len(payload))
+	}
+
+	m, err := Decode(payload)
+	if err != nil {
+		log.Printf("gelf: discarding UDP datagram: %s", err)
+		if r.OnRejected != nil {
+			r.OnRejected()
+		}
+		return
+	}
+	r.Handler(m)
+}
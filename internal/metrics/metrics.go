@@ -0,0 +1,52 @@
+// Package metrics tracks simple operational counters for the running
+// adapter, logged periodically so operators can watch ingestion without
+// a separate metrics backend.
+package metrics
+
+import "sync/atomic"
+
+// Counters holds the counters for a single protocol (udp, tcp, http).
+type Counters struct {
+	Received      int64
+	Rejected      int64
+	ChunksEvicted int64
+
+	BytesCompressedIn    int64
+	BytesDecompressedOut int64
+}
+
+// Protocols tracks counters per input protocol.
+type Protocols struct {
+	UDP  Counters
+	TCP  Counters
+	HTTP Counters
+
+	// QueueDropped counts events discarded by the queue's saturation
+	// policy (drop-oldest/drop-new). Events that find no room when a
+	// batch is re-queued after exhausting its delivery retries are only
+	// logged by the forwarder and are not counted here.
+	QueueDropped int64
+}
+
+func (p *Protocols) AddQueueDropped() { atomic.AddInt64(&p.QueueDropped, 1) }
+
+func (p *Protocols) QueueDroppedCount() int64 { return atomic.LoadInt64(&p.QueueDropped) }
+
+func (c *Counters) AddReceived()     { atomic.AddInt64(&c.Received, 1) }
+func (c *Counters) AddRejected()     { atomic.AddInt64(&c.Rejected, 1) }
+func (c *Counters) AddChunkEvicted() { atomic.AddInt64(&c.ChunksEvicted, 1) }
+
+func (c *Counters) AddDecompressed(compressedBytes, decompressedBytes int) {
+	atomic.AddInt64(&c.BytesCompressedIn, int64(compressedBytes))
+	atomic.AddInt64(&c.BytesDecompressedOut, int64(decompressedBytes))
+}
+
+func (c *Counters) Snapshot() Counters {
+	return Counters{
+		Received:             atomic.LoadInt64(&c.Received),
+		Rejected:             atomic.LoadInt64(&c.Rejected),
+		ChunksEvicted:        atomic.LoadInt64(&c.ChunksEvicted),
+		BytesCompressedIn:    atomic.LoadInt64(&c.BytesCompressedIn),
+		BytesDecompressedOut: atomic.LoadInt64(&c.BytesDecompressedOut),
+	}
+}
@@ -0,0 +1,107 @@
+// Package queue provides a bounded, policy-driven buffer between GELF
+// receivers and the Seq writer, so a burst on a fast transport (TCP,
+// HTTP) can't outrun ingestion without an explicit, operator-chosen
+// trade-off.
+package queue
+
+import "github.com/datalust/seq-input-gelf/internal/clef"
+
+// Policy controls what happens when Push is called against a full
+// queue.
+type Policy string
+
+const (
+	// PolicyBlock makes Push wait for room, applying backpressure to
+	// the caller (e.g. a slow HTTP 202 response).
+	PolicyBlock Policy = "block"
+	// PolicyDropOldest discards the longest-queued event to make room
+	// for the new one.
+	PolicyDropOldest Policy = "drop-oldest"
+	// PolicyDropNew discards the event being pushed, leaving the queue
+	// unchanged.
+	PolicyDropNew Policy = "drop-new"
+)
+
+// Queue is a bounded FIFO of translated events awaiting delivery to
+// Seq.
+type Queue struct {
+	ch     chan *clef.Event
+	policy Policy
+	onDrop func()
+}
+
+// New returns a queue with room for capacity events, applying policy
+// once it is full. onDrop, if non-nil, is called once for every event
+// the policy discards.
+func New(capacity int, policy Policy, onDrop func()) *Queue {
+	return &Queue{
+		ch:     make(chan *clef.Event, capacity),
+		policy: policy,
+		onDrop: onDrop,
+	}
+}
+
+// Push enqueues e, applying the queue's saturation policy if it is
+// full.
+func (q *Queue) Push(e *clef.Event) {
+	switch q.policy {
+	case PolicyDropNew:
+		select {
+		case q.ch <- e:
+		default:
+			q.dropped()
+		}
+
+	case PolicyDropOldest:
+		for {
+			select {
+			case q.ch <- e:
+				return
+			default:
+				select {
+				case <-q.ch:
+					q.dropped()
+				default:
+				}
+			}
+		}
+
+	default: // PolicyBlock
+		q.ch <- e
+	}
+}
+
+func (q *Queue) dropped() {
+	if q.onDrop != nil {
+		q.onDrop()
+	}
+}
+
+// TryPush enqueues e without blocking or applying the saturation
+// policy, reporting false if the queue was full. It's intended for
+// re-queueing a batch that failed delivery, where blocking the only
+// consumer would deadlock the queue.
+func (q *Queue) TryPush(e *clef.Event) bool {
+	select {
+	case q.ch <- e:
+		return true
+	default:
+		return false
+	}
+}
+
+// Full reports whether the queue is currently at capacity.
+func (q *Queue) Full() bool {
+	return len(q.ch) >= cap(q.ch)
+}
+
+// Chan exposes the queue for draining by a batching consumer.
+func (q *Queue) Chan() <-chan *clef.Event {
+	return q.ch
+}
+
+// Close signals that no further events will be pushed; a consumer
+// ranging over Chan observes it closing once drained.
+func (q *Queue) Close() {
+	close(q.ch)
+}
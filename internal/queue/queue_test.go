@@ -0,0 +1,114 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datalust/seq-input-gelf/internal/clef"
+)
+
+func event(template string) *clef.Event {
+	return &clef.Event{MessageTemplate: template}
+}
+
+func drain(t *testing.T, q *Queue) []string {
+	t.Helper()
+	var got []string
+	for {
+		select {
+		case e := <-q.Chan():
+			got = append(got, e.MessageTemplate)
+		default:
+			return got
+		}
+	}
+}
+
+func TestQueuePolicyDropNewDiscardsIncoming(t *testing.T) {
+	var dropped int
+	q := New(1, PolicyDropNew, func() { dropped++ })
+
+	q.Push(event("kept"))
+	q.Push(event("discarded"))
+
+	if dropped != 1 {
+		t.Fatalf("expected 1 drop, got %d", dropped)
+	}
+	if got := drain(t, q); len(got) != 1 || got[0] != "kept" {
+		t.Fatalf("got %v, want [kept]", got)
+	}
+}
+
+func TestQueuePolicyDropOldestDiscardsQueued(t *testing.T) {
+	var dropped int
+	q := New(1, PolicyDropOldest, func() { dropped++ })
+
+	q.Push(event("oldest"))
+	q.Push(event("newest"))
+
+	if dropped != 1 {
+		t.Fatalf("expected 1 drop, got %d", dropped)
+	}
+	if got := drain(t, q); len(got) != 1 || got[0] != "newest" {
+		t.Fatalf("got %v, want [newest]", got)
+	}
+}
+
+func TestQueuePolicyBlockWaitsForRoom(t *testing.T) {
+	q := New(1, PolicyBlock, nil)
+	q.Push(event("first"))
+
+	pushed := make(chan struct{})
+	go func() {
+		q.Push(event("second"))
+		close(pushed)
+	}()
+
+	select {
+	case <-pushed:
+		t.Fatal("Push should block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	<-q.Chan() // make room
+
+	select {
+	case <-pushed:
+	case <-time.After(time.Second):
+		t.Fatal("Push should have completed once room was made")
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	q := New(1, PolicyDropNew, nil)
+	if q.Full() {
+		t.Fatal("a fresh queue should not report full")
+	}
+	q.Push(event("one"))
+	if !q.Full() {
+		t.Fatal("queue at capacity should report full")
+	}
+}
+
+func TestQueueTryPush(t *testing.T) {
+	q := New(1, PolicyBlock, nil)
+	if !q.TryPush(event("one")) {
+		t.Fatal("TryPush into a queue with room should succeed")
+	}
+	if q.TryPush(event("two")) {
+		t.Fatal("TryPush into a full queue should report false, not block")
+	}
+}
+
+func TestQueueCloseDrainsThenCloses(t *testing.T) {
+	q := New(2, PolicyBlock, nil)
+	q.Push(event("one"))
+	q.Close()
+
+	if e, ok := <-q.Chan(); !ok || e.MessageTemplate != "one" {
+		t.Fatalf("expected to drain the buffered event, got %v, ok=%v", e, ok)
+	}
+	if _, ok := <-q.Chan(); ok {
+		t.Fatal("channel should be closed once drained")
+	}
+}
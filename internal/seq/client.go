@@ -0,0 +1,86 @@
+// Package seq forwards translated CLEF events to a Seq server's raw
+// ingestion endpoint.
+package seq
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/datalust/seq-input-gelf/internal/clef"
+)
+
+// Client posts batches of CLEF events to a Seq server.
+type Client struct {
+	// URL is the base address of the Seq server, e.g.
+	// "https://seq.example.com".
+	URL string
+	// APIKey is sent as the X-Seq-ApiKey header, if set.
+	APIKey string
+
+	HTTPClient *http.Client
+}
+
+// ErrThrottled is returned by Send when Seq responds 429 (Too Many
+// Requests) or 503 (Service Unavailable), both of which indicate the
+// batch should be retried rather than discarded.
+type ErrThrottled struct {
+	StatusCode int
+}
+
+func (e *ErrThrottled) Error() string {
+	return fmt.Sprintf("seq: server returned %d, try again later", e.StatusCode)
+}
+
+// Send marshals events as newline-delimited CLEF and posts them to
+// Seq's raw ingestion endpoint.
+func (c *Client) Send(events []*clef.Event) error {
+	var buf bytes.Buffer
+	for _, e := range events {
+		b, err := e.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("seq: encoding event: %w", err)
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+
+	url := strings.TrimRight(c.URL, "/") + "/api/events/raw?clef"
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return fmt.Errorf("seq: building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.serilog.clef")
+	if c.APIKey != "" {
+		req.Header.Set("X-Seq-ApiKey", c.APIKey)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("seq: sending batch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		io.Copy(io.Discard, resp.Body)
+		return &ErrThrottled{StatusCode: resp.StatusCode}
+	}
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("seq: server returned %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	return nil
+}
+
+// DefaultHTTPClient is a reasonable default for Client.HTTPClient.
+var DefaultHTTPClient = &http.Client{Timeout: 30 * time.Second}
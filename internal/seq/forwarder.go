@@ -0,0 +1,88 @@
+package seq
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/datalust/seq-input-gelf/internal/clef"
+	"github.com/datalust/seq-input-gelf/internal/queue"
+)
+
+// maxSendAttempts bounds how many times a single batch is retried
+// against a throttled (429/503) Seq server before it is re-queued for
+// a later attempt.
+const maxSendAttempts = 5
+
+// Forwarder drains a queue.Queue in batches and delivers them to Seq,
+// retrying throttled sends with backoff and re-queueing batches that
+// exhaust their retries.
+type Forwarder struct {
+	Queue         *queue.Queue
+	Client        *Client
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+// Run drains the queue until it is closed, delivering batches to Seq.
+// It blocks and should be run from its own goroutine.
+func (f *Forwarder) Run() {
+	batch := make([]*clef.Event, 0, f.BatchSize)
+	ticker := time.NewTicker(f.FlushInterval)
+	defer ticker.Stop()
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		f.send(batch)
+		batch = make([]*clef.Event, 0, f.BatchSize)
+	}
+
+	for {
+		select {
+		case e, ok := <-f.Queue.Chan():
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) >= f.BatchSize {
+				flush()
+			}
+
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (f *Forwarder) send(batch []*clef.Event) {
+	backoff := 500 * time.Millisecond
+
+	for attempt := 1; attempt <= maxSendAttempts; attempt++ {
+		err := f.Client.Send(batch)
+		if err == nil {
+			return
+		}
+
+		var throttled *ErrThrottled
+		if !errors.As(err, &throttled) {
+			log.Printf("seq: dropping batch of %d events: %s", len(batch), err)
+			return
+		}
+
+		log.Printf("seq: %s (attempt %d/%d)", err, attempt, maxSendAttempts)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	requeued := 0
+	for _, e := range batch {
+		if f.Queue.TryPush(e) {
+			requeued++
+		}
+	}
+	log.Printf("seq: re-queued %d/%d events after %d failed attempts, %d dropped for lack of room",
+		requeued, len(batch), maxSendAttempts, len(batch)-requeued)
+}